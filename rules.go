@@ -0,0 +1,107 @@
+// Copyright ©2017 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package typo
+
+// FoldCounting specifies how Enzyme.Fold accumulates the folding direction
+// contributed by each amino acid in an enzyme.
+type FoldCounting int
+
+const (
+	// CountAllSegments accumulates the kink of every amino acid in the
+	// enzyme, including the first, when computing the folding direction
+	// of its last segment. This is the choice that reproduces the
+	// preference given for the example on p508 of GEB.
+	CountAllSegments FoldCounting = iota
+	// CountTrailingSegments treats the first amino acid's kink as
+	// establishing the enzyme's initial heading rather than as a turn,
+	// and accumulates only the kinks of the amino acids that follow it.
+	CountTrailingSegments
+)
+
+// EdgeBehaviour specifies how Enzyme.OperateOn resolves a situation that
+// Hofstadter's text leaves unspecified: the current position landing on a
+// gap, whether because a Cut or Del put it there, a Swi switched onto one,
+// or a search amino acid failed to find a match before running off the
+// strand.
+type EdgeBehaviour int
+
+const (
+	// Terminate ends the operation at the edge case, dissociating the
+	// complex as it stands, as GEB's worked examples do.
+	Terminate EdgeBehaviour = iota
+	// Continue carries on to the enzyme's next amino acid regardless of
+	// the edge case, operating from the same position, or from the
+	// nearest edge cell if the position is off the strand.
+	Continue
+	// Panic refuses to resolve the edge case, panicking instead.
+	Panic
+)
+
+// Rules specifies a dialect of typogenetics semantics, resolving the
+// folding ambiguity and the operational edge cases that GEB's description
+// of typogenetics leaves unspecified. It is consumed by Enzyme.Fold,
+// Enzyme.Preference and Enzyme.OperateOn.
+type Rules struct {
+	// Name identifies the dialect, for diagnostics.
+	Name string
+
+	// Folding specifies how Enzyme.Fold counts segments.
+	Folding FoldCounting
+	// PanicOnAmbiguousFold makes Enzyme.Fold panic, rather than silently
+	// pick a FoldCounting reading, when an enzyme's first amino acid
+	// turns (Kinks[e[0]] != Straight) - the condition under which
+	// CountAllSegments and CountTrailingSegments diverge.
+	PanicOnAmbiguousFold bool
+
+	// EmptyCut specifies the behaviour when a Cut leaves the current
+	// position on a gap.
+	EmptyCut EdgeBehaviour
+	// EmptyDel specifies the behaviour when a Del leaves the current
+	// position on a gap.
+	EmptyDel EdgeBehaviour
+	// GapSwitch specifies the behaviour when a Swi lands the current
+	// position on a gap.
+	GapSwitch EdgeBehaviour
+	// NoMatch specifies the behaviour when a search amino acid (Rpy, Rpu,
+	// Lpy or Lpu) runs off the strand without finding a match.
+	NoMatch EdgeBehaviour
+}
+
+var (
+	// RulesGEB reproduces the behaviour described by Hofstadter's worked
+	// examples: folding counts all segments, and every edge case
+	// terminates the operation.
+	RulesGEB = Rules{
+		Name:    "GEB",
+		Folding: CountAllSegments,
+	}
+
+	// RulesSnare is the unambiguous specification given by the Monash
+	// typogenetics thesis: folding counts all segments, as in RulesGEB,
+	// but each edge case has a canonical, non-terminating resolution.
+	RulesSnare = Rules{
+		Name:      "Snare",
+		Folding:   CountAllSegments,
+		EmptyCut:  Continue,
+		EmptyDel:  Continue,
+		GapSwitch: Continue,
+		NoMatch:   Continue,
+	}
+
+	// RulesStrict refuses to silently resolve any ambiguity or edge
+	// case, panicking instead: folding counts all segments as a nominal
+	// choice, but Enzyme.Fold panics whenever an enzyme actually
+	// exercises the ambiguity that choice resolves, and every
+	// operational edge case panics too.
+	RulesStrict = Rules{
+		Name:                 "Strict",
+		Folding:              CountAllSegments,
+		PanicOnAmbiguousFold: true,
+		EmptyCut:             Panic,
+		EmptyDel:             Panic,
+		GapSwitch:            Panic,
+		NoMatch:              Panic,
+	}
+)
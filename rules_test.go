@@ -0,0 +1,74 @@
+// Copyright ©2017 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package typo
+
+import (
+	"reflect"
+	"testing"
+)
+
+var rulesComparisonTests = []struct {
+	rules  Rules
+	enzyme Enzyme
+	strand Strand
+	pos    int
+	want   []Strand
+}{
+	// A Cut at the right-hand end of the strand leaves the current
+	// position on a gap; RulesGEB and RulesSnare disagree on whether
+	// that ends the operation.
+	{
+		rules:  RulesGEB,
+		enzyme: Enzyme{Cut},
+		strand: Strand("AA"),
+		pos:    1,
+		want:   []Strand{Strand("AA")},
+	},
+	{
+		rules:  RulesSnare,
+		enzyme: Enzyme{Cut, Mvl, Del},
+		strand: Strand("AA"),
+		pos:    1,
+		want:   []Strand{Strand("A")},
+	},
+}
+
+func TestRulesComparison(t *testing.T) {
+	for _, test := range rulesComparisonTests {
+		got := test.enzyme.OperateOn(test.rules, NewComplex(test.strand), test.pos, nil).Products()
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("unexpected result for %s dialect:\ngot: %q\nwant:%q", test.rules.Name, got, test.want)
+		}
+	}
+}
+
+func TestRulesStrictPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic operating under RulesStrict on a gap-inducing enzyme")
+		}
+	}()
+	e := Enzyme{Cut}
+	e.OperateOn(RulesStrict, NewComplex(Strand("AA")), 1, nil)
+}
+
+func TestRulesStrictPanicsOnAmbiguousFold(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic folding under RulesStrict an enzyme whose first amino acid turns")
+		}
+	}()
+	// Rpy's kink is Right, so CountAllSegments and CountTrailingSegments
+	// disagree about this enzyme's fold.
+	e := Enzyme{Rpy, Ina, Rpu, Mvr, Int, Mvl, Cut, Swi, Cop}
+	e.Fold(RulesStrict)
+}
+
+func TestRulesStrictFoldsUnambiguousEnzyme(t *testing.T) {
+	// Ina's kink is Straight, so the two FoldCounting readings agree and
+	// RulesStrict should fold it without panicking.
+	e := Enzyme{Ina, Rpu, Cop, Inc, Swi}
+	e.Fold(RulesStrict)
+}
@@ -0,0 +1,385 @@
+// Copyright ©2017 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package search explores the space of typogenetics strands for sequences
+// whose operation on themselves satisfies a behavioural predicate, such as
+// self-replication (a quine), production of a specific target strand, or
+// production of a minimum number of distinct products.
+package search
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+
+	"github.com/kortschak/typo"
+)
+
+// bases lists the four bases strands are drawn from.
+var bases = [4]byte{'A', 'C', 'G', 'T'}
+
+// Predicate reports whether the products a strand yields when operated on
+// by its own enzymes satisfy a search goal.
+type Predicate func(parent typo.Strand, products []typo.Strand) bool
+
+// Quine returns a Predicate satisfied when parent appears among products,
+// i.e. the strand reproduces itself.
+func Quine() Predicate {
+	return func(parent typo.Strand, products []typo.Strand) bool {
+		for _, p := range products {
+			if string(p) == string(parent) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Targets returns a Predicate satisfied when target appears among
+// products.
+func Targets(target typo.Strand) Predicate {
+	return func(_ typo.Strand, products []typo.Strand) bool {
+		for _, p := range products {
+			if string(p) == string(target) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MinProducts returns a Predicate satisfied when products contains at
+// least n distinct strands.
+func MinProducts(n int) Predicate {
+	return func(_ typo.Strand, products []typo.Strand) bool {
+		seen := make(map[string]bool, len(products))
+		for _, p := range products {
+			seen[string(p)] = true
+		}
+		return len(seen) >= n
+	}
+}
+
+// Exec returns the products of operating, in turn, every enzyme encoded
+// in s on s, each enzyme binding at the first position along s holding
+// its preferred base and being skipped if no such position exists. Unlike
+// Enzyme.OperateOn, Exec does not consume s: it operates on a copy.
+func Exec(rules typo.Rules, s typo.Strand) []typo.Strand {
+	enzymes := s.Enzymes()
+	c := typo.NewComplex(append(typo.Strand(nil), s...))
+	for _, e := range enzymes {
+		pref := e.Preference(rules)
+		for i, b := range c[0] {
+			if b == pref {
+				c = e.OperateOn(rules, c, i, nil)
+				break
+			}
+		}
+	}
+	return c.Products()
+}
+
+// Canonical returns a dedupliction key for s: the lexicographically
+// smaller of s and its reverse complement, since a strand and the strand
+// complementary to it denote the same physical duplex.
+func Canonical(s typo.Strand) string {
+	rc := reverseComplement(s)
+	a, b := string(s), string(rc)
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func reverseComplement(s typo.Strand) typo.Strand {
+	rc := make(typo.Strand, len(s))
+	for i, b := range s {
+		rc[len(s)-i-1] = typo.Complement[b]()
+	}
+	return rc
+}
+
+// Enumerate exhaustively searches strands over {A,C,G,T} of length at
+// most maxLen, depth first with bases tried in the order A, C, G, T, for
+// one whose Exec result satisfies pred. It returns the first matching
+// strand found and true, or a nil Strand and false if none exists up to
+// maxLen. The first match in this order is not necessarily the shortest:
+// every strand starting with "A" is inspected before "C".
+//
+// Enumerate inspects every prefix as its own candidate, not just those of
+// length maxLen, before extending it, and abandons the walk the instant
+// one satisfies pred, so no extension of a matching prefix is constructed.
+// Beyond that, it does not prune subtrees: pred is an arbitrary opaque
+// function, and with every remaining position free to be any base, no
+// prefix can be proven hopeless - including on preference-mismatch
+// grounds - before maxLen is reached, so doing so would silently turn
+// "exhaustive" into "heuristic". Callers who can tolerate that trade, or
+// who need to explore beyond small values of maxLen, should use RunGA or
+// ParallelSearch instead.
+//
+// The search space has size proportional to 4^maxLen, so Enumerate is
+// only practical for small values of maxLen.
+func Enumerate(rules typo.Rules, maxLen int, pred Predicate) (typo.Strand, bool) {
+	buf := make(typo.Strand, 0, maxLen)
+	var found typo.Strand
+	var ok bool
+
+	var walk func()
+	walk = func() {
+		if ok {
+			return
+		}
+		if len(buf) > 0 {
+			// Inspect this prefix's products before descending any
+			// further, so a match is reported as soon as it exists.
+			s := append(typo.Strand(nil), buf...)
+			if pred(s, Exec(rules, s)) {
+				found, ok = s, true
+				return
+			}
+		}
+		if len(buf) == maxLen {
+			return
+		}
+		for _, b := range bases {
+			buf = append(buf, b)
+			walk()
+			buf = buf[:len(buf)-1]
+			if ok {
+				// A match was found somewhere below; stop trying the
+				// remaining sibling bases at every level on the way
+				// back out.
+				return
+			}
+		}
+	}
+	walk()
+
+	return found, ok
+}
+
+// ParallelSearch runs workers goroutines, each drawing random strands of
+// length maxLen from an independently seeded source and evaluating pred
+// against them, until a match is found or every worker has exhausted
+// budget attempts. A strand's Canonical form is recorded in a set shared
+// between all workers so that no two workers evaluate the same duplex
+// twice. It returns the first matching strand found and true, or a nil
+// Strand and false if budget is exhausted without a match.
+func ParallelSearch(rules typo.Rules, workers, budget, maxLen int, seed int64, pred Predicate) (typo.Strand, bool) {
+	type result struct {
+		s  typo.Strand
+		ok bool
+	}
+
+	visited := newVisitedSet()
+	found := make(chan result, workers)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func(w int) {
+			defer wg.Done()
+			rnd := rand.New(rand.NewSource(seed + int64(w)))
+			for i := 0; i < budget; i++ {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				s := randomStrand(rnd, maxLen)
+				if !visited.addIfAbsent(Canonical(s)) {
+					continue
+				}
+				if pred(s, Exec(rules, s)) {
+					select {
+					case found <- result{s, true}:
+					case <-done:
+					}
+					return
+				}
+			}
+		}(w)
+	}
+
+	go func() {
+		wg.Wait()
+		close(found)
+	}()
+
+	r, ok := <-found
+	close(done)
+	if !ok {
+		return nil, false
+	}
+	return r.s, r.ok
+}
+
+func randomStrand(rnd *rand.Rand, length int) typo.Strand {
+	s := make(typo.Strand, length)
+	for i := range s {
+		s[i] = bases[rnd.Intn(len(bases))]
+	}
+	return s
+}
+
+// visitedSet is a set of strand keys shared safely between search
+// workers.
+type visitedSet struct {
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+func newVisitedSet() *visitedSet {
+	return &visitedSet{seen: make(map[string]bool)}
+}
+
+// addIfAbsent reports whether key had not previously been added, adding
+// it if so.
+func (v *visitedSet) addIfAbsent(key string) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	absent := !v.seen[key]
+	v.seen[key] = true
+	return absent
+}
+
+// Fitness scores a strand for use by RunGA; higher scores are fitter.
+type Fitness func(parent typo.Strand, products []typo.Strand) float64
+
+// HeuristicFitness returns a Fitness combining the density of enzyme
+// binding-preference matches along a strand with the number of enzymes
+// it encodes, as a general-purpose default for RunGA.
+func HeuristicFitness(rules typo.Rules) Fitness {
+	return func(parent typo.Strand, _ []typo.Strand) float64 {
+		enzymes := parent.Enzymes()
+		if len(enzymes) == 0 || len(parent) == 0 {
+			return 0
+		}
+		var matches int
+		for _, e := range enzymes {
+			pref := e.Preference(rules)
+			for _, b := range parent {
+				if b == pref {
+					matches++
+				}
+			}
+		}
+		density := float64(matches) / float64(len(parent))
+		return density + float64(len(enzymes))
+	}
+}
+
+// GAOptions configures RunGA.
+type GAOptions struct {
+	// Generations is the number of generations to evolve.
+	Generations int
+	// MutationRate is the probability, per base per generation, that a
+	// point mutation (base substitution, insertion or deletion) is
+	// applied to a child strand.
+	MutationRate float64
+	// Rand is the source of randomness used for selection, crossover and
+	// mutation. If nil, a default source is used.
+	Rand *rand.Rand
+}
+
+// RunGA evolves population toward maximising fitness over opts.Generations
+// generations, using tournament selection, crossover at Non-codon
+// boundaries, and point mutation, and returns the fittest strand seen
+// across every generation.
+func RunGA(rules typo.Rules, population []typo.Strand, fitness Fitness, opts GAOptions) typo.Strand {
+	rnd := opts.Rand
+	if rnd == nil {
+		rnd = rand.New(rand.NewSource(1))
+	}
+
+	pop := make([]typo.Strand, len(population))
+	copy(pop, population)
+
+	var best typo.Strand
+	bestScore := math.Inf(-1)
+
+	for g := 0; g < opts.Generations; g++ {
+		scores := make([]float64, len(pop))
+		for i, s := range pop {
+			scores[i] = fitness(s, Exec(rules, s))
+			if scores[i] > bestScore {
+				bestScore, best = scores[i], s
+			}
+		}
+
+		next := make([]typo.Strand, 0, len(pop))
+		for len(next) < len(pop) {
+			a := tournament(rnd, pop, scores)
+			b := tournament(rnd, pop, scores)
+			child := crossover(rnd, a, b)
+			child = mutate(rnd, child, opts.MutationRate)
+			next = append(next, child)
+		}
+		pop = next
+	}
+
+	return best
+}
+
+func tournament(rnd *rand.Rand, pop []typo.Strand, scores []float64) typo.Strand {
+	i, j := rnd.Intn(len(pop)), rnd.Intn(len(pop))
+	if scores[i] >= scores[j] {
+		return pop[i]
+	}
+	return pop[j]
+}
+
+// nonCodonBoundaries returns the positions in s immediately following
+// each "AA" duet that falls on an even codon boundary - the points at
+// which Strand.Enzymes ends one enzyme and starts looking for the next.
+func nonCodonBoundaries(s typo.Strand) []int {
+	var at []int
+	for i := 0; i+1 < len(s); i += 2 {
+		if s[i] == 'A' && s[i+1] == 'A' {
+			at = append(at, i+2)
+		}
+	}
+	return at
+}
+
+// crossover returns a child strand formed by taking a from the start up
+// to a Non-codon boundary chosen at random, then b from that point on. If
+// a has no such boundary, a copy of a is returned unchanged.
+func crossover(rnd *rand.Rand, a, b typo.Strand) typo.Strand {
+	boundaries := nonCodonBoundaries(a)
+	if len(boundaries) == 0 {
+		return append(typo.Strand(nil), a...)
+	}
+	cut := boundaries[rnd.Intn(len(boundaries))]
+	child := append(typo.Strand(nil), a[:cut]...)
+	if cut < len(b) {
+		child = append(child, b[cut:]...)
+	}
+	return child
+}
+
+// mutate returns a copy of s in which each base, independently with
+// probability rate, is substituted, followed by an insertion, or deleted,
+// the three being equally likely.
+func mutate(rnd *rand.Rand, s typo.Strand, rate float64) typo.Strand {
+	out := append(typo.Strand(nil), s...)
+	for i := 0; i < len(out); i++ {
+		if rnd.Float64() >= rate {
+			continue
+		}
+		switch rnd.Intn(3) {
+		case 0: // substitution
+			out[i] = bases[rnd.Intn(len(bases))]
+		case 1: // insertion
+			b := bases[rnd.Intn(len(bases))]
+			out = append(out[:i+1], append(typo.Strand{b}, out[i+1:]...)...)
+		case 2: // deletion
+			out = append(out[:i], out[i+1:]...)
+			i--
+		}
+	}
+	return out
+}
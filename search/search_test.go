@@ -0,0 +1,64 @@
+// Copyright ©2017 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package search
+
+import (
+	"testing"
+
+	"github.com/kortschak/typo"
+)
+
+func TestExecQuine(t *testing.T) {
+	// The Example_quine strand in the parent package self-replicates
+	// under exactly this binding policy.
+	seed := typo.Strand("CGTTCCTCTCTCTCTATAGAGAGAGAGGAACG")
+	products := Exec(typo.RulesGEB, seed)
+	if !Quine()(seed, products) {
+		t.Errorf("expected %q to be amongst its own products, got:%q", seed, products)
+	}
+}
+
+func TestEnumerateFindsMinProducts(t *testing.T) {
+	got, ok := Enumerate(typo.RulesGEB, 4, MinProducts(2))
+	if !ok {
+		t.Fatal("expected to find a strand of length <= 4 yielding at least 2 products")
+	}
+	if n := len(Exec(typo.RulesGEB, got)); n < 2 {
+		t.Errorf("found strand %q yields only %d products", got, n)
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	s := typo.Strand("TAGC")
+	if got, want := Canonical(s), Canonical(reverseComplement(s)); got != want {
+		t.Errorf("strand and its reverse complement should share a canonical form: got:%q want:%q", got, want)
+	}
+}
+
+func TestParallelSearchFindsMinProducts(t *testing.T) {
+	got, ok := ParallelSearch(typo.RulesGEB, 4, 256, 10, 1, MinProducts(2))
+	if !ok {
+		t.Fatal("expected to find a strand yielding at least 2 products")
+	}
+	if n := len(Exec(typo.RulesGEB, got)); n < 2 {
+		t.Errorf("found strand %q yields only %d products", got, n)
+	}
+}
+
+func TestRunGADoesNotRegressFitness(t *testing.T) {
+	population := []typo.Strand{
+		typo.Strand("AAAAAAAAAA"),
+		typo.Strand("GATCCGGCAT"),
+		typo.Strand("AAAAAAAAAA"),
+		typo.Strand("AAAAAAAAAA"),
+	}
+	fitness := HeuristicFitness(typo.RulesGEB)
+	initial := fitness(population[1], Exec(typo.RulesGEB, population[1]))
+
+	best := RunGA(typo.RulesGEB, population, fitness, GAOptions{Generations: 10, MutationRate: 0.05})
+	if got := fitness(best, Exec(typo.RulesGEB, best)); got < initial {
+		t.Errorf("RunGA regressed fitness: got:%v initial:%v", got, initial)
+	}
+}
@@ -0,0 +1,332 @@
+// Copyright ©2017 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package typo
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+)
+
+// EnzymeOrder specifies the order in which the enzymes produced by a strand
+// are applied to that strand during a generation.
+type EnzymeOrder int
+
+const (
+	// BookOrder applies enzymes to the strand that produced them in the
+	// left-to-right order in which they occur in the strand, as described
+	// in GEB.
+	BookOrder EnzymeOrder = iota
+	// AllOrders applies enzymes to the strand that produced them in every
+	// possible order, each order contributing its own products to the
+	// next generation's pool.
+	AllOrders
+	// RandomOrder applies enzymes to the strand that produced them in a
+	// random order drawn from the Simulator's random source.
+	RandomOrder
+)
+
+func (o EnzymeOrder) String() string {
+	switch o {
+	case BookOrder:
+		return "book order"
+	case AllOrders:
+		return "all orderings"
+	case RandomOrder:
+		return "random order"
+	default:
+		return fmt.Sprintf("EnzymeOrder(%d)", int(o))
+	}
+}
+
+// PreferencePolicy specifies how a binding position is chosen when an
+// enzyme's preferred base occurs at more than one position of the strand
+// it was produced from.
+type PreferencePolicy int
+
+const (
+	// FirstPreference binds the enzyme at the first position holding the
+	// preferred base.
+	FirstPreference PreferencePolicy = iota
+	// AllPreferences binds the enzyme at every position holding the
+	// preferred base, each binding contributing its own products to the
+	// next generation's pool.
+	AllPreferences
+	// RandomPreference binds the enzyme at a position holding the
+	// preferred base chosen at random from the Simulator's random source.
+	RandomPreference
+)
+
+func (p PreferencePolicy) String() string {
+	switch p {
+	case FirstPreference:
+		return "first preference"
+	case AllPreferences:
+		return "all preferences"
+	case RandomPreference:
+		return "random preference"
+	default:
+		return fmt.Sprintf("PreferencePolicy(%d)", int(p))
+	}
+}
+
+// CycleEvent describes a periodic orbit detected in the sequence of
+// generation pools produced by a Simulator.
+type CycleEvent struct {
+	// Generation is the generation at which the cycle was detected.
+	Generation int
+	// Period is the number of generations between repeats of the pool
+	// multiset.
+	Period int
+}
+
+// Simulator iterates a pool of strands through successive generations of
+// typogenetic reproduction, recording per-generation population histograms
+// and detecting steady-state and periodic behaviour.
+//
+// In each generation, every enzyme produced from a strand operates on that
+// strand at its preferred binding positions; the EnzymeOrder and
+// PreferencePolicy fields control, respectively, the order in which a
+// strand's enzymes are applied and which binding position is chosen when
+// more than one is available. The products of every operation are pooled
+// to form the next generation.
+type Simulator struct {
+	// Pool is the current generation's population of strands.
+	Pool []Strand
+
+	// EnzymeOrder specifies the order enzymes are applied in.
+	EnzymeOrder EnzymeOrder
+	// Preference specifies how multiple preferred binding positions
+	// are resolved.
+	Preference PreferencePolicy
+
+	// Rand is the source of randomness used by RandomOrder and
+	// RandomPreference. NewSimulator sets it from its seed; if it is nil
+	// when Step is called, a source seeded with 1 is used.
+	Rand *rand.Rand
+
+	// Rules selects the typogenetics dialect used to fold enzymes and
+	// resolve their operational edge cases. The zero value has the same
+	// semantics as RulesGEB.
+	Rules Rules
+
+	// Window bounds the number of most recent generations' pool hashes
+	// retained for cycle detection, and so the longest period
+	// detectCycle can find; a cycle longer than Window goes undetected.
+	// The zero value uses defaultWindow.
+	Window int
+
+	generation int
+	window     []uint64
+}
+
+// defaultWindow is the Window size used when Simulator.Window is zero.
+const defaultWindow = 64
+
+// NewSimulator returns a Simulator that iterates pool according to the
+// given order and preference policies, using seed to initialise its random
+// source.
+func NewSimulator(pool []Strand, order EnzymeOrder, pref PreferencePolicy, seed int64) *Simulator {
+	return &Simulator{
+		Pool:        pool,
+		EnzymeOrder: order,
+		Preference:  pref,
+		Rand:        rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Generation returns the index of the current generation, starting at 0
+// for the pool the Simulator was constructed with.
+func (s *Simulator) Generation() int { return s.generation }
+
+// Histogram returns the population counts of each distinct strand in the
+// current pool, keyed by the strand's string representation.
+func (s *Simulator) Histogram() map[string]int {
+	h := make(map[string]int, len(s.Pool))
+	for _, strand := range s.Pool {
+		h[string(strand)]++
+	}
+	return h
+}
+
+// poolHash returns a hash of the multiset of strands in the current pool,
+// order-independent, for use in cycle detection.
+func (s *Simulator) poolHash() uint64 {
+	keys := make([]string, len(s.Pool))
+	for i, strand := range s.Pool {
+		keys[i] = string(strand)
+	}
+	sort.Strings(keys)
+	h := fnv.New64a()
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s\x00", k)
+	}
+	return h.Sum64()
+}
+
+// detectCycle looks for a repeat of the current pool hash within the
+// sliding window of prior generation hashes, returning the corresponding
+// CycleEvent if one is found.
+func (s *Simulator) detectCycle() *CycleEvent {
+	hash := s.poolHash()
+	for i := len(s.window) - 1; i >= 0; i-- {
+		if s.window[i] == hash {
+			return &CycleEvent{Generation: s.generation, Period: len(s.window) - i}
+		}
+	}
+	s.window = append(s.window, hash)
+	if n := s.windowSize(); len(s.window) > n {
+		s.window = s.window[len(s.window)-n:]
+	}
+	return nil
+}
+
+// windowSize returns the effective Window size, applying defaultWindow
+// in place of the zero value.
+func (s *Simulator) windowSize() int {
+	if s.Window > 0 {
+		return s.Window
+	}
+	return defaultWindow
+}
+
+// Step advances the Simulator by one generation, replacing Pool with the
+// pooled products of every enzyme produced from every strand in the
+// current Pool operating at its preferred binding positions. It returns
+// the histogram of the generation prior to stepping and, if the pool
+// multiset matches one of the last Window generations' pools, the
+// detected CycleEvent.
+func (s *Simulator) Step() (histogram map[string]int, cycle *CycleEvent) {
+	if s.Rand == nil {
+		s.Rand = rand.New(rand.NewSource(1))
+	}
+	histogram = s.Histogram()
+	cycle = s.detectCycle()
+
+	var next []Strand
+	for _, strand := range s.Pool {
+		next = append(next, s.react(strand)...)
+	}
+	s.Pool = next
+	s.generation++
+
+	return histogram, cycle
+}
+
+// react returns the products of applying every enzyme produced from strand
+// to strand, according to the Simulator's EnzymeOrder and Preference
+// policies. Under AllPreferences, each candidate binding position forks
+// its own complex, so that each binding contributes its own independent
+// products rather than compounding with the others.
+func (s *Simulator) react(strand Strand) []Strand {
+	enzymes := strand.Enzymes()
+	if len(enzymes) == 0 {
+		return nil
+	}
+
+	var products []Strand
+	for _, order := range s.orderings(enzymes) {
+		c := NewComplex(append(Strand(nil), strand...))
+		for _, result := range s.bind(c, order) {
+			products = append(products, result.Products()...)
+		}
+	}
+	return products
+}
+
+// bind applies order's enzymes to c in turn, forking a fresh copy of c
+// for every candidate binding position each enzyme has, and returns every
+// resulting complex.
+func (s *Simulator) bind(c Complex, order []Enzyme) []Complex {
+	if len(order) == 0 {
+		return []Complex{c}
+	}
+	e, rest := order[0], order[1:]
+
+	positions := s.positions(c[0], e.Preference(s.Rules))
+	if len(positions) == 0 {
+		return s.bind(c, rest)
+	}
+
+	var results []Complex
+	for _, pos := range positions {
+		bound := e.OperateOn(s.Rules, copyComplex(c), pos, nil)
+		results = append(results, s.bind(bound, rest)...)
+	}
+	return results
+}
+
+// copyComplex returns a copy of c whose strands do not alias c's.
+func copyComplex(c Complex) Complex {
+	return Complex{
+		append(Strand(nil), c[0]...),
+		append(Strand(nil), c[1]...),
+	}
+}
+
+// orderings returns the sequences of enzymes to apply for a strand's
+// enzyme set, according to the Simulator's EnzymeOrder policy.
+func (s *Simulator) orderings(enzymes []Enzyme) [][]Enzyme {
+	switch s.EnzymeOrder {
+	case AllOrders:
+		return permutations(enzymes)
+	case RandomOrder:
+		order := make([]Enzyme, len(enzymes))
+		copy(order, enzymes)
+		s.Rand.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+		return [][]Enzyme{order}
+	default: // BookOrder
+		return [][]Enzyme{enzymes}
+	}
+}
+
+// positions returns the strand positions at which pref occurs, chosen
+// according to the Simulator's Preference policy.
+func (s *Simulator) positions(strand Strand, pref byte) []int {
+	var all []int
+	for i, b := range strand {
+		if b == pref {
+			all = append(all, i)
+		}
+	}
+	switch s.Preference {
+	case AllPreferences:
+		return all
+	case RandomPreference:
+		if len(all) == 0 {
+			return nil
+		}
+		i := s.Rand.Intn(len(all))
+		return all[i : i+1]
+	default: // FirstPreference
+		if len(all) == 0 {
+			return nil
+		}
+		return all[:1]
+	}
+}
+
+// permutations returns every permutation of e.
+func permutations(e []Enzyme) [][]Enzyme {
+	if len(e) == 0 {
+		return nil
+	}
+	if len(e) == 1 {
+		return [][]Enzyme{{e[0]}}
+	}
+
+	var perms [][]Enzyme
+	for i := range e {
+		rest := make([]Enzyme, 0, len(e)-1)
+		rest = append(rest, e[:i]...)
+		rest = append(rest, e[i+1:]...)
+		for _, p := range permutations(rest) {
+			perm := append([]Enzyme{e[i]}, p...)
+			perms = append(perms, perm)
+		}
+	}
+	return perms
+}
@@ -0,0 +1,92 @@
+// Copyright ©2017 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package typo
+
+import "testing"
+
+func TestSimulatorQuine(t *testing.T) {
+	seed := "CGTTCCTCTCTCTCTATAGAGAGAGAGGAACG"
+	s := NewSimulator([]Strand{Strand(seed)}, BookOrder, FirstPreference, 1)
+
+	want := []int{1, 2, 4, 8, 16}
+	for n, w := range want {
+		hist, cycle := s.Step()
+		if got := hist[seed]; got != w {
+			t.Errorf("unexpected count at generation %d: got:%d want:%d", n, got, w)
+		}
+		if cycle != nil {
+			t.Errorf("unexpected cycle detected at generation %d: %+v", n, cycle)
+		}
+	}
+}
+
+func TestSimulatorAllOrdersIndependentProducts(t *testing.T) {
+	// "GATCCGGCATAAGATCCGGCAT" produces two identical enzymes from the
+	// two "GATCCGGCAT" copies either side of the "AA" spacer. Under
+	// AllOrders, applying them in the other order should yield the same
+	// symmetric products, not a strand corrupted by aliasing across
+	// orderings.
+	strand := "GATCCGGCATAAGATCCGGCAT"
+	s := NewSimulator([]Strand{Strand(strand)}, AllOrders, FirstPreference, 1)
+
+	_, cycle := s.Step()
+	if cycle != nil {
+		t.Errorf("unexpected cycle detected: %+v", cycle)
+	}
+
+	want := map[string]int{"GC": 2, "GATACCGCGCATAAGATCCGGCAT": 2}
+	got := s.Histogram()
+	if len(got) != len(want) {
+		t.Fatalf("unexpected number of distinct products: got:%d want:%d", len(got), len(want))
+	}
+	for strand, n := range want {
+		if got[strand] != n {
+			t.Errorf("unexpected count for %q: got:%d want:%d", strand, got[strand], n)
+		}
+	}
+}
+
+func TestSimulatorWindowBound(t *testing.T) {
+	// A period-3 cycle of distinct pools should go undetected once the
+	// repeat falls outside a Window of 2, demonstrating that the window
+	// is actually bounded rather than retaining full history.
+	s := &Simulator{Window: 2}
+	pools := [][]Strand{
+		{Strand("A")},
+		{Strand("C")},
+		{Strand("G")},
+		{Strand("A")}, // repeats pools[0], 3 generations back
+	}
+	for n, pool := range pools {
+		s.Pool = pool
+		cycle := s.detectCycle()
+		if n == len(pools)-1 && cycle != nil {
+			t.Errorf("unexpected cycle detected within bounded window: %+v", cycle)
+		}
+	}
+	if len(s.window) > 2 {
+		t.Errorf("window grew beyond its bound: got:%d want:<=2", len(s.window))
+	}
+}
+
+func TestSimulatorCycleDetection(t *testing.T) {
+	// "AA" contains no enzyme, so the pool goes extinct after the first
+	// generation and then repeats that empty pool forever - a period-1
+	// cycle.
+	s := NewSimulator([]Strand{Strand("AA")}, BookOrder, FirstPreference, 1)
+
+	var got *CycleEvent
+	for n := 0; n < 3; n++ {
+		_, cycle := s.Step()
+		if cycle != nil {
+			got = cycle
+			break
+		}
+	}
+	want := &CycleEvent{Generation: 2, Period: 1}
+	if got == nil || *got != *want {
+		t.Errorf("unexpected cycle event: got:%+v want:%+v", got, want)
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright ©2017 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package typo
+
+import "fmt"
+
+// SynthesizeOptions configures Synthesize's choice of non-coding filler.
+type SynthesizeOptions struct {
+	// Length pads the returned Strand with trailing non-coding codons
+	// until it is at least this long. A value of 0 or less performs no
+	// padding.
+	Length int
+}
+
+// bases lists the four bases in Code/index order, so bases[i] is the base
+// with index i.
+var bases = [4]byte{'A', 'C', 'G', 'T'}
+
+// nonCodon is the only codon that Code maps to Non.
+var nonCodon = []byte{'A', 'A'}
+
+// Synthesize returns a Strand that, when passed through Strand.Enzymes,
+// yields exactly the given enzymes, in order.
+//
+// Because Code maps each of the 16 two-base codons to a distinct
+// AminoAcid, the codon encoding an amino acid is unique: unlike natural
+// genetic codes, there is no synonymous codon to choose between to bias
+// base composition or avoid a motif within a coding region. The only
+// freedom Synthesize has is in the non-coding filler it uses to separate
+// successive enzymes and, via opts.Length, to pad the result; both use
+// repeats of "AA", the only codon that decodes to Non.
+//
+// Synthesize returns an error if enzymes contains an enzyme with no amino
+// acids, or an amino acid not present in Code.
+func Synthesize(enzymes []Enzyme, opts SynthesizeOptions) (Strand, error) {
+	var s Strand
+	for i, e := range enzymes {
+		if len(e) == 0 {
+			return nil, fmt.Errorf("typo: enzyme %d is empty", i)
+		}
+		if i != 0 {
+			s = append(s, nonCodon...)
+		}
+		for _, a := range e {
+			if a == Non {
+				return nil, fmt.Errorf("typo: enzyme %d contains Non", i)
+			}
+			codon, err := codonFor(a)
+			if err != nil {
+				return nil, fmt.Errorf("typo: enzyme %d: %w", i, err)
+			}
+			s = append(s, codon[0], codon[1])
+		}
+	}
+	for len(s) < opts.Length {
+		s = append(s, nonCodon...)
+	}
+	return s, nil
+}
+
+// codonFor returns the unique codon that Code maps to a.
+func codonFor(a AminoAcid) ([2]byte, error) {
+	for i, c := range Code {
+		if c == a {
+			return [2]byte{bases[i/4], bases[i%4]}, nil
+		}
+	}
+	return [2]byte{}, fmt.Errorf("no codon encodes %s", a)
+}
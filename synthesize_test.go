@@ -0,0 +1,59 @@
+// Copyright ©2017 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package typo
+
+import (
+	"reflect"
+	"testing"
+)
+
+var synthesizeTests = []struct {
+	enzymes []Enzyme
+	opts    SynthesizeOptions
+	want    Strand
+}{
+	{
+		enzymes: []Enzyme{{Ina, Rpu, Cop, Inc, Swi}},
+		want:    Strand("GATCCGGCAT"),
+	},
+	{
+		enzymes: []Enzyme{
+			{Rpy, Ina, Rpu, Mvr, Int, Mvl, Cut, Swi, Cop},
+			{Ina, Rpu, Cop, Inc, Swi},
+		},
+	},
+	{
+		enzymes: []Enzyme{{Ina, Rpu, Cop, Inc, Swi}},
+		opts:    SynthesizeOptions{Length: 14},
+	},
+}
+
+func TestSynthesize(t *testing.T) {
+	for _, test := range synthesizeTests {
+		got, err := Synthesize(test.enzymes, test.opts)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			continue
+		}
+		if test.want != nil && !reflect.DeepEqual(got, test.want) {
+			t.Errorf("unexpected strand:\ngot: %q\nwant:%q", got, test.want)
+		}
+		if len(got) < test.opts.Length {
+			t.Errorf("strand shorter than requested length: got:%d want at least:%d", len(got), test.opts.Length)
+		}
+		if gotEnzymes := got.Enzymes(); !reflect.DeepEqual(gotEnzymes, test.enzymes) {
+			t.Errorf("synthesized strand does not round-trip:\ngot: %v\nwant:%v", gotEnzymes, test.enzymes)
+		}
+	}
+}
+
+func TestSynthesizeErrors(t *testing.T) {
+	if _, err := Synthesize([]Enzyme{{}}, SynthesizeOptions{}); err == nil {
+		t.Error("expected error synthesizing an empty enzyme")
+	}
+	if _, err := Synthesize([]Enzyme{{Non}}, SynthesizeOptions{}); err == nil {
+		t.Error("expected error synthesizing an enzyme containing Non")
+	}
+}
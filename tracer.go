@@ -0,0 +1,204 @@
+// Copyright ©2017 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package typo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// Reason identifies why Enzyme.OperateOn stopped operating, whether at
+// the end of the enzyme or part way through it, or, in a FaultEvent, the
+// kind of position an edge case left it at.
+type Reason int
+
+const (
+	// Completed indicates that every amino acid in the enzyme was
+	// executed.
+	Completed Reason = iota
+	// OffStrand indicates that the current position ran off the strand
+	// entirely.
+	OffStrand
+	// EmptyCell indicates that the current position landed on a gap.
+	EmptyCell
+)
+
+func (r Reason) String() string {
+	switch r {
+	case Completed:
+		return "completed"
+	case OffStrand:
+		return "off strand"
+	case EmptyCell:
+		return "empty cell"
+	default:
+		return fmt.Sprintf("Reason(%d)", int(r))
+	}
+}
+
+// StepEvent describes the amino acid Enzyme.OperateOn is about to
+// execute.
+type StepEvent struct {
+	Amino    AminoAcid
+	Pos      int
+	CopyMode bool
+	Strands  [2]Strand
+}
+
+// FaultEvent describes a gap or off-strand position the current position
+// landed on that rules allowed the operation to continue past.
+type FaultEvent struct {
+	Amino   AminoAcid
+	Pos     int
+	Reason  Reason
+	Strands [2]Strand
+}
+
+// DoneEvent describes how Enzyme.OperateOn ended.
+type DoneEvent struct {
+	Pos     int
+	Reason  Reason
+	Strands [2]Strand
+}
+
+// Tracer receives the sequence of events generated by a single call to
+// Enzyme.OperateOn: one StepEvent per amino acid, a FaultEvent for each
+// gap or off-strand position that rules resolved by continuing, and
+// exactly one DoneEvent when
+// the operation ends.
+type Tracer interface {
+	OnStep(StepEvent)
+	OnFault(FaultEvent)
+	OnDone(DoneEvent)
+}
+
+// TextTracer writes the tabular trace format used by earlier versions of
+// this package's debug output.
+type TextTracer struct {
+	w *tabwriter.Writer
+}
+
+// NewTextTracer returns a TextTracer that writes to w.
+func NewTextTracer(w io.Writer) *TextTracer {
+	return &TextTracer{w: tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)}
+}
+
+func (t *TextTracer) OnStep(e StepEvent) {
+	fmt.Fprintf(t.w, "%s\t%4d\t%c\t%q\t%q\n", e.Amino, e.Pos, cellAt(e.Strands[0], e.Pos), e.Strands[0], e.Strands[1])
+}
+
+func (t *TextTracer) OnFault(e FaultEvent) {
+	fmt.Fprintf(t.w, "continue\t%4d\t·\t%q\t%q\n", e.Pos, e.Strands[0], e.Strands[1])
+}
+
+func (t *TextTracer) OnDone(e DoneEvent) {
+	switch e.Reason {
+	case EmptyCell:
+		fmt.Fprintf(t.w, "empty\t%4d\t·\t%q\t%q\n", e.Pos, e.Strands[0], e.Strands[1])
+	case OffStrand:
+		fmt.Fprintf(t.w, "off\t%4d\t-\t%q\t%q\n", e.Pos, e.Strands[0], e.Strands[1])
+	default:
+		fmt.Fprintf(t.w, "done\t%4d\t%c\t%q\t%q\n", e.Pos, cellAt(e.Strands[0], e.Pos), e.Strands[0], e.Strands[1])
+	}
+	t.w.Flush()
+}
+
+// cellAt returns the display byte for position pos of s: the base at
+// pos, '·' if pos is on the strand but the cell is a gap, or '-' if pos
+// is off the strand.
+func cellAt(s Strand, pos int) byte {
+	if pos < 0 || pos >= len(s) {
+		return '-'
+	}
+	if s[pos] == 0 {
+		return '·'
+	}
+	return s[pos]
+}
+
+// JSONLTracer writes one JSON object per line for each event, for
+// machine consumption.
+type JSONLTracer struct {
+	enc *json.Encoder
+}
+
+// NewJSONLTracer returns a JSONLTracer that writes to w.
+func NewJSONLTracer(w io.Writer) *JSONLTracer {
+	return &JSONLTracer{enc: json.NewEncoder(w)}
+}
+
+func (t *JSONLTracer) OnStep(e StepEvent) {
+	t.enc.Encode(struct {
+		Type string `json:"type"`
+		StepEvent
+	}{"step", e})
+}
+
+func (t *JSONLTracer) OnFault(e FaultEvent) {
+	t.enc.Encode(struct {
+		Type string `json:"type"`
+		FaultEvent
+	}{"fault", e})
+}
+
+func (t *JSONLTracer) OnDone(e DoneEvent) {
+	t.enc.Encode(struct {
+		Type string `json:"type"`
+		DoneEvent
+	}{"done", e})
+}
+
+// EventKind identifies which field of an Event recorded by a
+// RecordingTracer is populated.
+type EventKind int
+
+const (
+	StepKind EventKind = iota
+	FaultKind
+	DoneKind
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case StepKind:
+		return "step"
+	case FaultKind:
+		return "fault"
+	case DoneKind:
+		return "done"
+	default:
+		return fmt.Sprintf("EventKind(%d)", int(k))
+	}
+}
+
+// Event is a single event recorded by a RecordingTracer. Kind indicates
+// which of Step, Fault and Done is populated.
+type Event struct {
+	Kind  EventKind
+	Step  StepEvent
+	Fault FaultEvent
+	Done  DoneEvent
+}
+
+// RecordingTracer keeps the sequence of events from one or more
+// Enzyme.OperateOn calls in memory, for programmatic inspection. Simulator
+// does not take a Tracer, so the operations it performs are not recorded.
+type RecordingTracer struct {
+	Events []Event
+}
+
+func (t *RecordingTracer) OnStep(e StepEvent) {
+	t.Events = append(t.Events, Event{Kind: StepKind, Step: e})
+}
+
+func (t *RecordingTracer) OnFault(e FaultEvent) {
+	t.Events = append(t.Events, Event{Kind: FaultKind, Fault: e})
+}
+
+func (t *RecordingTracer) OnDone(e DoneEvent) {
+	t.Events = append(t.Events, Event{Kind: DoneKind, Done: e})
+}
@@ -0,0 +1,63 @@
+// Copyright ©2017 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package typo
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRecordingTracer(t *testing.T) {
+	e := Enzyme{Rpu, Inc, Cop, Mvr, Mvl, Swi, Lpu, Int}
+	var rec RecordingTracer
+	e.OperateOn(RulesGEB, NewComplex(Strand("TAGATCCAGTCCATCGA")), 8, &rec)
+
+	if len(rec.Events) != len(e)+1 {
+		t.Fatalf("unexpected number of events: got:%d want:%d", len(rec.Events), len(e)+1)
+	}
+	for i, ev := range rec.Events[:len(e)] {
+		if ev.Kind != StepKind {
+			t.Errorf("event %d: got kind %s, want %s", i, ev.Kind, StepKind)
+		}
+		if ev.Step.Amino != e[i] {
+			t.Errorf("event %d: got amino acid %s, want %s", i, ev.Step.Amino, e[i])
+		}
+	}
+	last := rec.Events[len(e)]
+	if last.Kind != DoneKind {
+		t.Errorf("unexpected final event kind: got:%s want:%s", last.Kind, DoneKind)
+	}
+	if last.Done.Reason != Completed {
+		t.Errorf("unexpected completion reason: got:%s want:%s", last.Done.Reason, Completed)
+	}
+}
+
+func TestJSONLTracer(t *testing.T) {
+	var buf bytes.Buffer
+	e := Enzyme{Ina, Rpu, Cop, Inc, Swi}
+	e.OperateOn(RulesGEB, NewComplex(Strand("GATCCGGCAT")), 2, NewJSONLTracer(&buf))
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != len(e)+1 {
+		t.Fatalf("unexpected number of lines: got:%d want:%d", len(lines), len(e)+1)
+	}
+	for i, line := range lines {
+		var ev struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			t.Fatalf("line %d does not decode as JSON: %v", i, err)
+		}
+		want := "step"
+		if i == len(lines)-1 {
+			want = "done"
+		}
+		if ev.Type != want {
+			t.Errorf("line %d: unexpected event type: got:%q want:%q", i, ev.Type, want)
+		}
+	}
+}
@@ -9,7 +9,6 @@ package typo
 import (
 	"bytes"
 	"fmt"
-	"text/tabwriter"
 )
 
 // AminoAcid represents a typogenetics amino acid.
@@ -183,8 +182,16 @@ func (e Enzyme) String() string {
 	return buf.String()
 }
 
-// Fold returns the first and last segment folding directions of the receiver.
-func (e Enzyme) Fold() (first, last Direction) {
+// Fold returns the first and last segment folding directions of the
+// receiver, resolved according to rules.
+// Fold will panic if rules.PanicOnAmbiguousFold is set and the receiver's
+// first amino acid turns, the condition under which rules.Folding's two
+// readings diverge.
+func (e Enzyme) Fold(rules Rules) (first, last Direction) {
+	if rules.PanicOnAmbiguousFold && Kinks[e[0]] != Straight {
+		panic(fmt.Sprintf("typo: ambiguous folding for enzyme starting with %s", e[0]))
+	}
+
 	dir := North
 	// The text is ambiguous about the behaviour here; the folding
 	// example on p511 gives Rpy-Ina-Rpu-Mvr-Int-Mvl-Cut-Swi-Cop
@@ -195,17 +202,24 @@ func (e Enzyme) Fold() (first, last Direction) {
 	// with the enzyme Rpu-Inc-Cop-Mvr-Mvl-Swi-Lpu-Int which Hofstadter
 	// says has a preference for 'G', meaning it must have a ⇒⇓
 	// fold. The only way to obtain this result is to count all
-	// segments.
-	for _, d := range e {
+	// segments; rules.Folding selects between that reading and one
+	// that treats the first segment as establishing the heading rather
+	// than as a turn.
+	segments := e
+	if rules.Folding == CountTrailingSegments {
+		segments = e[1:]
+	}
+	for _, d := range segments {
 		dir += Direction(Kinks[d])
 		dir &= 0x3
 	}
 	return Direction(Kinks[e[0]] & 0x3), dir
 }
 
-// Preference returns the base preference of the receiver.
-func (e Enzyme) Preference() byte {
-	first, last := e.Fold()
+// Preference returns the base preference of the receiver, resolved
+// according to rules.
+func (e Enzyme) Preference(rules Rules) byte {
+	first, last := e.Fold(rules)
 	t := Kink(East - first)
 	return Preference[(last+Direction(t))&0x3]()
 }
@@ -213,24 +227,20 @@ func (e Enzyme) Preference() byte {
 // OperateOn performs the enzymatic activity of the receiver on the given
 // typogenetics complex starting from the specified position of the first
 // strand of the complex according to rules of typogenetics on pp504-513
-// of GEB and returns the resulting product complex.
-// If debug is not nil, the sequence of operations and the intermediate
-// results are written into the buffer.
+// of GEB and returns the resulting product complex. Edge cases that GEB
+// leaves unspecified are resolved according to rules.
+// If tracer is not nil, it is sent the sequence of events generated by
+// the operation; see Tracer.
 // OperateOn will panic if the receiver includes an unknown amino acid
-// or the Non amino acid.
-func (e Enzyme) OperateOn(c Complex, pos int, debug *bytes.Buffer) Complex {
+// or the Non amino acid, or if rules resolves an edge case with Panic.
+func (e Enzyme) OperateOn(rules Rules, c Complex, pos int, tracer Tracer) Complex {
 	copyMode := false
 	if len(c[0]) != len(c[1]) {
 		panic("typo: invalid Complex: length mismatch")
 	}
-	var w *tabwriter.Writer
-	if debug != nil {
-		w = tabwriter.NewWriter(debug, 0, 0, 1, ' ', 0)
-	}
-	completed := true
 	for _, cmd := range e {
-		if w != nil {
-			fmt.Fprintf(w, "%s\t%4d\t%c\t%q\t%q\n", cmd, pos, c[0][pos], c[0], c[1])
+		if tracer != nil {
+			tracer.OnStep(StepEvent{Amino: cmd, Pos: pos, CopyMode: copyMode, Strands: snapshot(c)})
 		}
 
 		switch cmd {
@@ -269,43 +279,83 @@ func (e Enzyme) OperateOn(c Complex, pos int, debug *bytes.Buffer) Complex {
 			}
 		}
 		if !onStrand(c[0], pos) {
-			if w != nil {
-				if 0 <= pos && pos < len(c[0]) {
-					fmt.Fprintf(w, "empty\t%4d\t·\t%q\t%q\n", pos, c[0], c[1])
-				} else {
-					fmt.Fprintf(w, "off\t%4d\t-\t%q\t%q\n", pos, c[0], c[1])
+			reason := EmptyCell
+			if pos < 0 || pos >= len(c[0]) {
+				reason = OffStrand
+			}
+			switch edgeBehaviour(rules, cmd) {
+			case Continue:
+				if tracer != nil {
+					tracer.OnFault(FaultEvent{Amino: cmd, Pos: pos, Reason: reason, Strands: snapshot(c)})
 				}
+				if reason == OffStrand {
+					// There is no position to hold at off the strand,
+					// so clamp to the nearest edge cell instead.
+					pos = clamp(pos, len(c[0]))
+				}
+				continue
+			case Panic:
+				panic(fmt.Sprintf("typo: %s left position %d at %s", cmd, pos, reason))
+			}
+			if tracer != nil {
+				tracer.OnDone(DoneEvent{Pos: pos, Reason: reason, Strands: snapshot(c)})
 			}
-			completed = false
-			break
+			return c
 		}
 		if copyMode {
 			copyOpposite(c[1], c[0], pos)
 		}
 	}
-	if w != nil {
-		if completed {
-			var b byte
-			if 0 <= pos && pos < len(c[0]) {
-				b = c[0][pos]
-				if b == 0 {
-					b = '·'
-				}
-			} else {
-				b = '-'
-			}
-			fmt.Fprintf(w, "done\t%4d\t%c\t%q\t%q\n", pos, b, c[0], c[1])
-		}
-		w.Flush()
+	if tracer != nil {
+		tracer.OnDone(DoneEvent{Pos: pos, Reason: Completed, Strands: snapshot(c)})
 	}
 
 	return c
 }
 
+// snapshot returns a copy of c's strands, safe from subsequent mutation
+// of c.
+func snapshot(c Complex) [2]Strand {
+	return [2]Strand{
+		append(Strand(nil), c[0]...),
+		append(Strand(nil), c[1]...),
+	}
+}
+
+// edgeBehaviour returns the EdgeBehaviour that rules assigns to the gap
+// left by cmd landing the current position on an empty cell.
+func edgeBehaviour(rules Rules, cmd AminoAcid) EdgeBehaviour {
+	switch cmd {
+	case Cut, Ina, Inc, Ing, Int:
+		return rules.EmptyCut
+	case Del:
+		return rules.EmptyDel
+	case Swi:
+		return rules.GapSwitch
+	case Rpy, Rpu, Lpy, Lpu:
+		return rules.NoMatch
+	default:
+		return Terminate
+	}
+}
+
 func onStrand(s Strand, pos int) bool {
 	return 0 <= pos && pos < len(s) && s[pos] != 0
 }
 
+// clamp confines pos to the valid index range [0, n), holding at the
+// nearest edge.
+func clamp(pos, n int) int {
+	switch {
+	case pos < 0:
+		return 0
+	case pos >= n:
+		return n - 1
+	default:
+		return pos
+	}
+}
+
 // Strand is a typogenetics base strand.
 type Strand []byte
 
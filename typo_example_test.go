@@ -13,8 +13,8 @@ func Example_book() {
 	e := Enzyme{Rpu, Inc, Cop, Mvr, Mvl, Swi, Lpu, Int}
 	s := "TAGATCCAGTCCATCGA"
 
-	first, last := e.Fold()
-	pref := e.Preference()
+	first, last := e.Fold(RulesGEB)
+	pref := e.Preference(RulesGEB)
 	fmt.Printf("Folding:\n First:%s Last:%s\n\nPrefers:%q\n\n", first, last, pref)
 
 	var pos []int
@@ -32,7 +32,7 @@ func Example_book() {
 		// being operated on is consumed by the enzyme
 		// so this example creates a new Strand from
 		// a string constate for each start position.
-		products := e.OperateOn(NewComplex(Strand(s)), p, &buf).Products()
+		products := e.OperateOn(RulesGEB, NewComplex(Strand(s)), p, NewTextTracer(&buf)).Products()
 		fmt.Printf("%s\nProducts:%q\n\n", &buf, products)
 	}
 
@@ -96,10 +96,10 @@ func Example_quine() {
 			m := s.Enzymes()
 			c := NewComplex(s)
 			for _, e := range m {
-				pref := e.Preference()
+				pref := e.Preference(RulesGEB)
 				for i, b := range c[0] {
 					if b == pref {
-						c = e.OperateOn(c, i, nil)
+						c = e.OperateOn(RulesGEB, c, i, nil)
 						break
 					}
 				}
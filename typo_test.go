@@ -21,7 +21,7 @@ var foldingTests = []struct {
 
 func TestEnzymeFold(t *testing.T) {
 	for _, test := range foldingTests {
-		_, got := test.enzyme.Fold()
+		_, got := test.enzyme.Fold(RulesGEB)
 		if got != test.want {
 			t.Errorf("unexpected fold direction: got:%q want:%q", got, test.want)
 		}
@@ -39,7 +39,7 @@ var preferenceTests = []struct {
 
 func TestEnzymePreference(t *testing.T) {
 	for _, test := range preferenceTests {
-		got := test.enzyme.Preference()
+		got := test.enzyme.Preference(RulesGEB)
 		if got != test.want {
 			t.Errorf("unexpected preference: got:%q want:%q", got, test.want)
 		}
@@ -213,10 +213,34 @@ var operateOnTests = []struct {
 func TestOperateOn(t *testing.T) {
 	for _, test := range operateOnTests {
 		var buf bytes.Buffer
-		got := test.enzyme.OperateOn(NewComplex(test.strand), test.pos, &buf).Products()
+		got := test.enzyme.OperateOn(RulesGEB, NewComplex(test.strand), test.pos, NewTextTracer(&buf)).Products()
 		if !reflect.DeepEqual(got, test.want) {
 			t.Errorf("unexpected operation results:\ngot: %q\nwant:%q", got, test.want)
 			t.Logf("\n%s", &buf)
 		}
 	}
 }
+
+var operateOnOffStrandTests = []struct {
+	enzyme Enzyme
+	strand Strand
+	pos    int
+	rules  Rules
+	want   []Strand
+}{
+	// Rpy runs off the right end of the strand without finding a
+	// pyrimidine; RulesGEB terminates there, while RulesSnare's
+	// Continue resolution for NoMatch holds the position at the edge
+	// cell and carries on to the following Ina.
+	{enzyme: Enzyme{Rpy, Ina}, strand: Strand("AA"), pos: 0, rules: RulesGEB, want: []Strand{Strand("AA")}},
+	{enzyme: Enzyme{Rpy, Ina}, strand: Strand("AA"), pos: 0, rules: RulesSnare, want: []Strand{Strand("AAA")}},
+}
+
+func TestOperateOnOffStrand(t *testing.T) {
+	for _, test := range operateOnOffStrandTests {
+		got := test.enzyme.OperateOn(test.rules, NewComplex(test.strand), test.pos, nil).Products()
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("unexpected operation results for %s:\ngot: %q\nwant:%q", test.rules.Name, got, test.want)
+		}
+	}
+}